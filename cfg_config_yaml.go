@@ -0,0 +1,79 @@
+// Copyright (c) 2012 - Cloud Instruments Co., Ltd.
+//
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package seelog
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoggerFromYAMLConfigAsFile creates logger with config from file. File should
+// contain a seelog config tree encoded as YAML instead of XML.
+func LoggerFromYAMLConfigAsFile(fileName string) (LoggerInterface, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	conf, err := configFromYAMLReader(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return createLoggerFromConfig(conf)
+}
+
+// LoggerFromYAMLConfigAsBytes creates a logger with config from bytes stream.
+// Bytes should contain a seelog config tree encoded as YAML.
+func LoggerFromYAMLConfigAsBytes(data []byte) (LoggerInterface, error) {
+	conf, err := configFromYAMLReader(bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return createLoggerFromConfig(conf)
+}
+
+// LoggerFromYAMLConfigAsString creates a logger with config from a string.
+// String should contain a seelog config tree encoded as YAML.
+func LoggerFromYAMLConfigAsString(data string) (LoggerInterface, error) {
+	return LoggerFromYAMLConfigAsBytes([]byte(data))
+}
+
+// configFromYAMLReader decodes YAML from r into a configNode and hands it to
+// configFromNode, so YAML configs share validation, receiver lookup, level
+// exceptions, dispatcher assembly and formatter registration with XML ones.
+func configFromYAMLReader(r io.Reader) (*config, error) {
+	var root configNode
+	if err := yaml.NewDecoder(r).Decode(&root); err != nil {
+		return nil, err
+	}
+
+	return configFromNode(&root)
+}
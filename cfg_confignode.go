@@ -0,0 +1,86 @@
+// Copyright (c) 2012 - Cloud Instruments Co., Ltd.
+//
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package seelog
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+)
+
+// configNode is a format-neutral representation of a seelog config tree.
+// The JSON and YAML loaders decode into a configNode and then render it
+// back out as XML, so that JSON, YAML and XML configs are all validated,
+// resolved into receivers/formatters and assembled into a dispatcher tree
+// by exactly the same code path: configFromReader.
+type configNode struct {
+	Tag      string            `json:"tag" yaml:"tag"`
+	Attrs    map[string]string `json:"attrs,omitempty" yaml:"attrs,omitempty"`
+	Content  string            `json:"content,omitempty" yaml:"content,omitempty"`
+	Children []*configNode     `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// toXML renders the node tree as an XML document that configFromReader can
+// parse directly.
+func (n *configNode) toXML() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	n.writeXML(&buf)
+	return buf.Bytes()
+}
+
+func (n *configNode) writeXML(buf *bytes.Buffer) {
+	fmt.Fprintf(buf, "<%s", n.Tag)
+
+	attrNames := make([]string, 0, len(n.Attrs))
+	for name := range n.Attrs {
+		attrNames = append(attrNames, name)
+	}
+	sort.Strings(attrNames)
+	for _, name := range attrNames {
+		fmt.Fprintf(buf, " %s=\"", name)
+		xml.EscapeText(buf, []byte(n.Attrs[name]))
+		buf.WriteByte('"')
+	}
+
+	if len(n.Children) == 0 && n.Content == "" {
+		buf.WriteString("/>")
+		return
+	}
+
+	buf.WriteByte('>')
+	xml.EscapeText(buf, []byte(n.Content))
+	for _, child := range n.Children {
+		child.writeXML(buf)
+	}
+	fmt.Fprintf(buf, "</%s>", n.Tag)
+}
+
+// configFromNode turns a decoded configNode tree into a *config by routing
+// it through the XML config parser.
+func configFromNode(n *configNode) (*config, error) {
+	return configFromReader(bytes.NewReader(n.toXML()))
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2012 - Cloud Instruments Co., Ltd.
+//
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package seelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const testConfigNodeJSON = `{
+  "tag": "seelog",
+  "attrs": {"minlevel": "trace", "maxlevel": "critical"},
+  "children": [
+    {
+      "tag": "outputs",
+      "attrs": {"formatid": "main"},
+      "children": [
+        {"tag": "file", "attrs": {"path": "C:\\logs\\app.log"}}
+      ]
+    },
+    {
+      "tag": "formats",
+      "children": [
+        {"tag": "format", "attrs": {"id": "main", "format": "%Date %Time [%LEV] %Msg%n"}}
+      ]
+    }
+  ]
+}`
+
+const testConfigNodeYAML = `
+tag: seelog
+attrs:
+  minlevel: trace
+  maxlevel: critical
+children:
+  - tag: outputs
+    attrs:
+      formatid: main
+    children:
+      - tag: file
+        attrs:
+          path: 'C:\logs\app.log'
+  - tag: formats
+    children:
+      - tag: format
+        attrs:
+          id: main
+          format: "%Date %Time [%LEV] %Msg%n"
+`
+
+// TestJSONAndYAMLConfigNodesProduceIdenticalXML confirms that the same
+// logical config, encoded once as JSON and once as YAML, decodes into
+// configNode trees that render to byte-identical XML — the document
+// configFromJSONReader and configFromYAMLReader hand to the same
+// configFromReader an equivalent seelog.xml config would produce.
+func TestJSONAndYAMLConfigNodesProduceIdenticalXML(t *testing.T) {
+	var jsonNode, yamlNode configNode
+
+	if err := json.Unmarshal([]byte(testConfigNodeJSON), &jsonNode); err != nil {
+		t.Fatalf("decoding JSON config: %v", err)
+	}
+	if err := yaml.Unmarshal([]byte(testConfigNodeYAML), &yamlNode); err != nil {
+		t.Fatalf("decoding YAML config: %v", err)
+	}
+
+	jsonXML := jsonNode.toXML()
+	yamlXML := yamlNode.toXML()
+
+	if !bytes.Equal(jsonXML, yamlXML) {
+		t.Fatalf("JSON and YAML configs produced different XML:\nJSON: %s\nYAML: %s", jsonXML, yamlXML)
+	}
+}
+
+// TestConfigNodeEscapesAttributeValues guards against attribute values being
+// Go-string-quoted instead of XML-escaped: backslashes must survive
+// untouched, and embedded quotes must become valid XML instead of breaking
+// the attribute out of its quotes.
+func TestConfigNodeEscapesAttributeValues(t *testing.T) {
+	n := &configNode{
+		Tag:   "file",
+		Attrs: map[string]string{"path": `C:\logs\app.log`, "note": `say "hi"`},
+	}
+
+	out := n.toXML()
+
+	if !bytes.Contains(out, []byte(`C:\logs\app.log`)) {
+		t.Fatalf("backslashes were mangled: %s", out)
+	}
+	if bytes.Contains(out, []byte(`\"`)) {
+		t.Fatalf("quotes were Go-escaped instead of XML-escaped: %s", out)
+	}
+	if !bytes.Contains(out, []byte(`&#34;hi&#34;`)) {
+		t.Fatalf("embedded quotes were not XML-escaped: %s", out)
+	}
+}
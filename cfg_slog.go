@@ -0,0 +1,265 @@
+// Copyright (c) 2012 - Cloud Instruments Co., Ltd.
+//
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package seelog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// slogCallerSkip is the number of innermost frames (runtime.Callers, dispatch,
+// the slogReceiver method and fieldsCarryingReceiver.ReceiveMessage) that
+// dispatch skips before walking up to the first frame outside package seelog,
+// so the slog.Record built for h carries the caller's PC rather than one
+// somewhere inside seelog's frontend.
+const slogCallerSkip = 4
+
+// slogTraceLevel and slogCriticalLevel extend slog's Debug/Info/Warn/Error
+// scale with the two seelog levels slog has no constant for.
+const (
+	slogTraceLevel    = slog.Level(-8)
+	slogCriticalLevel = slog.Level(12)
+)
+
+// slogLevel maps a seelog LogLevel to the equivalent slog.Level.
+func slogLevel(level LogLevel) slog.Level {
+	switch level {
+	case TraceLvl:
+		return slogTraceLevel
+	case DebugLvl:
+		return slog.LevelDebug
+	case InfoLvl:
+		return slog.LevelInfo
+	case WarnLvl:
+		return slog.LevelWarn
+	case ErrorLvl:
+		return slog.LevelError
+	case CriticalLvl:
+		return slogCriticalLevel
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// slogReceiver is a CustomReceiver that forwards every seelog message to a
+// wrapped slog.Handler. It also implements FieldsReceiver, so fields
+// attached via Tracew/Debugw/.../Criticalw (see cfg_structured.go) reach h
+// as real slog.Attrs instead of being rendered into the message text.
+type slogReceiver struct {
+	handler slog.Handler
+}
+
+func (r *slogReceiver) ReceiveMessage(message string, level LogLevel, context LogContextInterface) error {
+	return r.dispatch(message, level, context.CallTime(), nil)
+}
+
+func (r *slogReceiver) ReceiveMessageWithFields(message string, level LogLevel, fields Fields) error {
+	return r.dispatch(message, level, time.Now(), fields)
+}
+
+func (r *slogReceiver) dispatch(message string, level LogLevel, callTime time.Time, fields Fields) error {
+	lvl := slogLevel(level)
+	if !r.handler.Enabled(ctxBackground, lvl) {
+		return nil
+	}
+
+	var pcs [32]uintptr
+	n := runtime.Callers(slogCallerSkip, pcs[:])
+	seelogPrefix := reflect.TypeOf(slogReceiver{}).PkgPath() + "."
+	var pc uintptr
+	for _, p := range pcs[:n] {
+		if fn := runtime.FuncForPC(p - 1); fn == nil || !strings.HasPrefix(fn.Name(), seelogPrefix) {
+			pc = p
+			break
+		}
+	}
+
+	record := slog.NewRecord(callTime, lvl, message, pc)
+	record.AddAttrs(slogAttrsFromFields(fields)...)
+	return r.handler.Handle(ctxBackground, record)
+}
+
+func slogAttrsFromFields(fields Fields) []slog.Attr {
+	attrs := make([]slog.Attr, len(fields))
+	for i, f := range fields {
+		attrs[i] = slog.Any(f.Key, f.Value)
+	}
+	return attrs
+}
+
+func (r *slogReceiver) AfterParse(initArgs CustomReceiverInitArgs) error { return nil }
+func (r *slogReceiver) Flush()                                          {}
+func (r *slogReceiver) Close() error                                    { return nil }
+
+// ctxBackground is used for the context.Context parameter slog.Handler
+// requires; seelog itself has no notion of request-scoped contexts.
+var ctxBackground = context.Background()
+
+// LoggerFromSlogHandler creates a proxy logger that forwards every message
+// with level >= minLevel to h as a slog.Record, preserving the call site and
+// mapping seelog levels onto the slog level scale. The returned
+// StructuredLoggerInterface's Tracew/Debugw/.../Criticalw calls (see
+// cfg_structured.go) forward their keysAndValues to h as slog.Attrs.
+//
+// Can be called for usage with non-Seelog systems.
+func LoggerFromSlogHandler(h slog.Handler, minLevel LogLevel) (StructuredLoggerInterface, error) {
+	constraints, err := newMinMaxConstraints(minLevel, CriticalLvl)
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped := newFieldsCarryingReceiver(&slogReceiver{handler: h})
+
+	output, err := newCustomReceiverDispatcherByValue(msgonlyformatter, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	dispatcher, err := newSplitDispatcher(msgonlyformatter, []interface{}{output})
+	if err != nil {
+		return nil, err
+	}
+
+	conf, err := newConfig(constraints, make([]*logLevelException, 0), dispatcher, syncloggerTypeFromString, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := createLoggerFromConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contextLogger{LoggerInterface: base, receiver: wrapped}, nil
+}
+
+// Handler is a slog.Handler backed by a seelog LoggerInterface: every Handle
+// call is translated into the matching Trace/Debug/Info/Warn/Error/Critical
+// call on the wrapped logger, with accumulated attrs rendered into the
+// message text.
+type Handler struct {
+	logger LoggerInterface
+	attrs  []slog.Attr
+	// groups are all the names passed to WithGroup so far, innermost last.
+	// They qualify attrs added by *subsequent* WithAttrs/Handle calls, per
+	// slog.Handler's contract; attrs already in attrs when a group was
+	// opened keep their unqualified keys.
+	groups []string
+}
+
+// NewSlogHandler wraps l as a slog.Handler, so libraries that log through
+// log/slog end up in l's dispatcher tree.
+func NewSlogHandler(l LoggerInterface) slog.Handler {
+	return &Handler{logger: l}
+}
+
+// Enabled always returns true: level filtering is left to l's own
+// constraints and exceptions, the same way a plain seelog call would be.
+func (h *Handler) Enabled(_ context.Context, _ slog.Level) bool {
+	return true
+}
+
+func (h *Handler) Handle(_ context.Context, record slog.Record) error {
+	var recordAttrs []slog.Attr
+	record.Attrs(func(a slog.Attr) bool {
+		recordAttrs = append(recordAttrs, a)
+		return true
+	})
+
+	attrs := make([]slog.Attr, 0, len(h.attrs)+len(recordAttrs))
+	attrs = append(attrs, h.attrs...)
+	attrs = append(attrs, prefixSlogAttrs(h.groups, recordAttrs)...)
+
+	msg := record.Message
+	if len(attrs) > 0 {
+		msg = msg + " " + renderSlogAttrs(attrs)
+	}
+
+	switch {
+	case record.Level < slog.LevelDebug:
+		return h.logger.Trace(msg)
+	case record.Level < slog.LevelInfo:
+		return h.logger.Debug(msg)
+	case record.Level < slog.LevelWarn:
+		return h.logger.Info(msg)
+	case record.Level < slog.LevelError:
+		return h.logger.Warn(msg)
+	case record.Level < slogCriticalLevel:
+		return h.logger.Error(msg)
+	default:
+		return h.logger.Critical(msg)
+	}
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	combined := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	combined = append(combined, h.attrs...)
+	combined = append(combined, prefixSlogAttrs(h.groups, attrs)...)
+	return &Handler{logger: h.logger, attrs: combined, groups: h.groups}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	groups := make([]string, 0, len(h.groups)+1)
+	groups = append(groups, h.groups...)
+	groups = append(groups, name)
+	return &Handler{logger: h.logger, attrs: h.attrs, groups: groups}
+}
+
+// prefixSlogAttrs qualifies each of attrs with groups, innermost-last, the
+// way slog.Handler's WithGroup contract requires: a group opened by
+// WithGroup applies to every attr added by a later WithAttrs or Handle call,
+// not to attrs that already existed when it was opened.
+func prefixSlogAttrs(groups []string, attrs []slog.Attr) []slog.Attr {
+	if len(groups) == 0 {
+		return attrs
+	}
+
+	prefix := strings.Join(groups, ".") + "."
+	out := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		out[i] = slog.Attr{Key: prefix + a.Key, Value: a.Value}
+	}
+	return out
+}
+
+func renderSlogAttrs(attrs []slog.Attr) string {
+	var buf bytes.Buffer
+	for i, a := range attrs {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		fmt.Fprintf(&buf, "%s=%v", a.Key, a.Value.Any())
+	}
+	return buf.String()
+}
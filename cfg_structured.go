@@ -0,0 +1,227 @@
+// Copyright (c) 2012 - Cloud Instruments Co., Ltd.
+//
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package seelog
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+)
+
+// Field is a single structured key-value pair attached to a log message by
+// the Tracew/Debugw/... family and by StructuredLoggerInterface.With.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Fields is a slice of Field in call order.
+type Fields []Field
+
+// fieldsFromKV pairs up keysAndValues into Fields. A dangling key with no
+// matching value is kept under the synthetic key "!BADKEY" rather than
+// panicking.
+func fieldsFromKV(keysAndValues []interface{}) Fields {
+	fields := make(Fields, 0, len(keysAndValues)/2+1)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprintf("%v", keysAndValues[i])
+		}
+		if i+1 >= len(keysAndValues) {
+			fields = append(fields, Field{Key: "!BADKEY", Value: key})
+			break
+		}
+		fields = append(fields, Field{Key: key, Value: keysAndValues[i+1]})
+	}
+	return fields
+}
+
+// renderFields renders fields as a space-separated list of k=v pairs, for
+// receivers that don't implement FieldsReceiver: logw appends this to the
+// message text instead of delivering the Fields slice structurally.
+func renderFields(fields Fields) string {
+	var buf bytes.Buffer
+	for i, f := range fields {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		fmt.Fprintf(&buf, "%s=%v", f.Key, f.Value)
+	}
+	return buf.String()
+}
+
+// FieldsReceiver is implemented by a CustomReceiver that wants the
+// structured key-value pairs passed to Tracew/Debugw/.../Criticalw in
+// addition to the formatted message. Receivers that don't implement it
+// still see the pairs, rendered as space-separated k=v text via
+// renderFields, appended to the message itself.
+type FieldsReceiver interface {
+	ReceiveMessageWithFields(message string, level LogLevel, fields Fields) error
+}
+
+// StructuredLoggerInterface extends LoggerInterface with key-value style
+// logging, mirroring the pattern popularized by go-kit/log and klog v2.
+// Loggers returned by LoggerFromCustomReceiverWithContext satisfy this
+// interface.
+type StructuredLoggerInterface interface {
+	LoggerInterface
+
+	Tracew(msg string, keysAndValues ...interface{}) error
+	Debugw(msg string, keysAndValues ...interface{}) error
+	Infow(msg string, keysAndValues ...interface{}) error
+	Warnw(msg string, keysAndValues ...interface{}) error
+	Errorw(msg string, keysAndValues ...interface{}) error
+	Criticalw(msg string, keysAndValues ...interface{}) error
+
+	// With returns a logger that prepends keysAndValues to every field
+	// recorded by a subsequent Tracew/Debugw/.../Criticalw call.
+	With(keysAndValues ...interface{}) StructuredLoggerInterface
+}
+
+// fieldsCarryingReceiver wraps a CustomReceiver so that the fields attached
+// to a Tracew/Debugw/.../Criticalw call reach its ReceiveMessageWithFields
+// (when it implements FieldsReceiver) without ever being delivered outside
+// the logger's normal dispatch path: dispatchWithFields stashes the fields
+// for the call about to happen and makes that call through the very same
+// LoggerInterface method (log) a plain Trace/Debug/.../Critical call would
+// use, so it is still the sync logger's own lock around receiver dispatch
+// that serializes access to the receiver, not a side channel in
+// contextLogger.
+type fieldsCarryingReceiver struct {
+	CustomReceiver
+	fields FieldsReceiver // non-nil if CustomReceiver also implements FieldsReceiver
+
+	mu      sync.Mutex
+	pending Fields
+	armed   bool
+}
+
+func newFieldsCarryingReceiver(receiver CustomReceiver) *fieldsCarryingReceiver {
+	fr, _ := receiver.(FieldsReceiver)
+	return &fieldsCarryingReceiver{CustomReceiver: receiver, fields: fr}
+}
+
+// ReceiveMessage is called by the logger's own (locked) dispatch for every
+// Trace/Debug/.../Critical call, both plain ones and ones routed here by
+// dispatchWithFields. When fields are pending for this call it hands them
+// to the wrapped receiver's ReceiveMessageWithFields instead of its plain
+// ReceiveMessage.
+func (r *fieldsCarryingReceiver) ReceiveMessage(message string, level LogLevel, context LogContextInterface) error {
+	if r.fields != nil && r.armed {
+		return r.fields.ReceiveMessageWithFields(message, level, r.pending)
+	}
+	return r.CustomReceiver.ReceiveMessage(message, level, context)
+}
+
+// dispatchWithFields arms the receiver with fields for the single call to
+// log it makes, holding mu for the full round trip so that no other
+// Tracew/Debugw/.../Criticalw or plain Trace/Debug/.../Critical call on a
+// logger sharing this receiver can observe or clobber fields meant for a
+// different call.
+func (r *fieldsCarryingReceiver) dispatchWithFields(log func(...interface{}) error, msg string, fields Fields) error {
+	if r.fields == nil {
+		if len(fields) == 0 {
+			return log(msg)
+		}
+		return log(msg + " " + renderFields(fields))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pending, r.armed = fields, true
+	defer func() { r.pending, r.armed = nil, false }()
+
+	return log(msg)
+}
+
+// contextLogger implements StructuredLoggerInterface around a plain
+// LoggerInterface and the fieldsCarryingReceiver backing it, accumulating
+// fields added via With.
+type contextLogger struct {
+	LoggerInterface
+	receiver *fieldsCarryingReceiver
+	fields   Fields
+}
+
+// LoggerFromCustomReceiverWithContext creates a proxy logger, like
+// LoggerFromCustomReceiver, whose returned StructuredLoggerInterface also
+// supports Tracew/Debugw/.../Criticalw and With. ctx is an initial set of
+// keysAndValues attached to every subsequent w-call, equivalent to calling
+// With(ctx...) on the result.
+func LoggerFromCustomReceiverWithContext(receiver CustomReceiver, ctx ...interface{}) (StructuredLoggerInterface, error) {
+	wrapped := newFieldsCarryingReceiver(receiver)
+
+	base, err := LoggerFromCustomReceiver(wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &contextLogger{LoggerInterface: base, receiver: wrapped}
+	if len(ctx) == 0 {
+		return l, nil
+	}
+	return l.With(ctx...), nil
+}
+
+func (l *contextLogger) With(keysAndValues ...interface{}) StructuredLoggerInterface {
+	combined := make(Fields, 0, len(l.fields)+len(keysAndValues)/2+1)
+	combined = append(combined, l.fields...)
+	combined = append(combined, fieldsFromKV(keysAndValues)...)
+	return &contextLogger{LoggerInterface: l.LoggerInterface, receiver: l.receiver, fields: combined}
+}
+
+func (l *contextLogger) logw(log func(...interface{}) error, msg string, keysAndValues []interface{}) error {
+	fields := make(Fields, 0, len(l.fields)+len(keysAndValues)/2+1)
+	fields = append(fields, l.fields...)
+	fields = append(fields, fieldsFromKV(keysAndValues)...)
+
+	return l.receiver.dispatchWithFields(log, msg, fields)
+}
+
+func (l *contextLogger) Tracew(msg string, keysAndValues ...interface{}) error {
+	return l.logw(l.LoggerInterface.Trace, msg, keysAndValues)
+}
+
+func (l *contextLogger) Debugw(msg string, keysAndValues ...interface{}) error {
+	return l.logw(l.LoggerInterface.Debug, msg, keysAndValues)
+}
+
+func (l *contextLogger) Infow(msg string, keysAndValues ...interface{}) error {
+	return l.logw(l.LoggerInterface.Info, msg, keysAndValues)
+}
+
+func (l *contextLogger) Warnw(msg string, keysAndValues ...interface{}) error {
+	return l.logw(l.LoggerInterface.Warn, msg, keysAndValues)
+}
+
+func (l *contextLogger) Errorw(msg string, keysAndValues ...interface{}) error {
+	return l.logw(l.LoggerInterface.Error, msg, keysAndValues)
+}
+
+func (l *contextLogger) Criticalw(msg string, keysAndValues ...interface{}) error {
+	return l.logw(l.LoggerInterface.Critical, msg, keysAndValues)
+}
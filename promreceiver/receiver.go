@@ -0,0 +1,162 @@
+// Copyright (c) 2012 - Cloud Instruments Co., Ltd.
+//
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Package promreceiver wires seelog up to Prometheus: every dispatched
+// message increments a counter labeled by level, without making the core
+// seelog package depend on github.com/prometheus/client_golang.
+//
+// Importing this package registers a "prometheus" custom receiver, so it
+// can also be wired up from XML/JSON/YAML config:
+//
+//	<custom name="prometheus" formatid="..."><param name="namespace" value="myapp"/></custom>
+package promreceiver
+
+import (
+	"fmt"
+
+	"github.com/pki-io/seelog"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusOpts configures the CounterVec created by
+// LoggerFromPrometheusReceiver and by the "prometheus" custom receiver when
+// it is wired up from config.
+type PrometheusOpts struct {
+	Namespace string
+	Subsystem string
+	Name      string
+	Help      string
+}
+
+func (o PrometheusOpts) withDefaults() PrometheusOpts {
+	if o.Name == "" {
+		o.Name = "log_messages_total"
+	}
+	if o.Help == "" {
+		o.Help = "Total number of log messages emitted, by level and component."
+	}
+	return o
+}
+
+// receiver is a seelog.CustomReceiver (and seelog.FieldsReceiver) that
+// increments counter once per dispatched message, labeled by level and, for
+// loggers obtained through LoggerFromPrometheusReceiver, by the "component"
+// field added via seelog's structured logging API (e.g.
+// logger.Infow("msg", "component", "ingest")). A receiver wired up from
+// XML/JSON/YAML config only ever sees ReceiveMessage, since a
+// config-loaded logger is a plain seelog.LoggerInterface with no Infow to
+// call — component is always "" on that path.
+type receiver struct {
+	counter *prometheus.CounterVec
+}
+
+func (r *receiver) ReceiveMessage(message string, level seelog.LogLevel, context seelog.LogContextInterface) error {
+	r.counter.WithLabelValues(level.String(), "").Inc()
+	return nil
+}
+
+func (r *receiver) ReceiveMessageWithFields(message string, level seelog.LogLevel, fields seelog.Fields) error {
+	component := ""
+	for _, f := range fields {
+		if f.Key == "component" {
+			component = fmt.Sprintf("%v", f.Value)
+			break
+		}
+	}
+	r.counter.WithLabelValues(level.String(), component).Inc()
+	return nil
+}
+
+// newCounterVec builds the CounterVec described by opts and registers it
+// against registerer, recovering the already-registered collector instead
+// of erroring when one with the same fully-qualified name already exists —
+// which happens routinely when a config (XML/JSON/YAML) carrying a
+// <custom name="prometheus".../> receiver is reloaded, since RegisterReceiver
+// instantiates a fresh receiver, and so builds a fresh CounterVec, on every
+// reload.
+func newCounterVec(registerer prometheus.Registerer, opts PrometheusOpts) (*prometheus.CounterVec, error) {
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: opts.Namespace,
+		Subsystem: opts.Subsystem,
+		Name:      opts.Name,
+		Help:      opts.Help,
+	}, []string{"level", "component"})
+
+	err := registerer.Register(counter)
+	if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+		existing, ok := are.ExistingCollector.(*prometheus.CounterVec)
+		if !ok {
+			return nil, fmt.Errorf("promreceiver: %s is already registered as a %T, not a *prometheus.CounterVec", opts.Name, are.ExistingCollector)
+		}
+		return existing, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return counter, nil
+}
+
+// AfterParse reads the namespace/subsystem/name/help params when this
+// receiver is instantiated by the config parser (XML/JSON/YAML) rather than
+// via LoggerFromPrometheusReceiver, and registers its counter against the
+// default Prometheus registerer.
+func (r *receiver) AfterParse(initArgs seelog.CustomReceiverInitArgs) error {
+	opts := PrometheusOpts{
+		Namespace: initArgs.XmlCustomAttrs["namespace"],
+		Subsystem: initArgs.XmlCustomAttrs["subsystem"],
+		Name:      initArgs.XmlCustomAttrs["name"],
+		Help:      initArgs.XmlCustomAttrs["help"],
+	}.withDefaults()
+
+	counter, err := newCounterVec(prometheus.DefaultRegisterer, opts)
+	if err != nil {
+		return err
+	}
+	r.counter = counter
+	return nil
+}
+
+func (r *receiver) Flush()       {}
+func (r *receiver) Close() error { return nil }
+
+func init() {
+	seelog.RegisterReceiver("prometheus", &receiver{})
+}
+
+// LoggerFromPrometheusReceiver creates a proxy logger whose receiver
+// increments a CounterVec, registered against registerer under opts,
+// labeled by level for every message dispatched, and additionally by
+// "component" for messages logged with logger.Infow("msg", "component",
+// name) (or Tracew/Debugw/Warnw/Errorw/Criticalw, or logger.With("component",
+// name)).
+func LoggerFromPrometheusReceiver(registerer prometheus.Registerer, opts PrometheusOpts) (seelog.StructuredLoggerInterface, error) {
+	opts = opts.withDefaults()
+
+	counter, err := newCounterVec(registerer, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return seelog.LoggerFromCustomReceiverWithContext(&receiver{counter: counter})
+}